@@ -0,0 +1,100 @@
+package sa
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// AllowedCallers maps the CommonName of a caller's verified mTLS client
+// certificate to the set of write operations (RPC names, e.g. "AddSerial",
+// "AddPrecertificate") that caller is permitted to invoke.
+// "AddPrecertificate" covers both the single-cert RPC and its
+// AddPrecertificateBatch implementation, since they're the same logical
+// write operation. A CommonName absent from this map is allowed no
+// operations.
+type AllowedCallers map[string][]string
+
+// allows reports whether commonName is permitted to perform op.
+func (a AllowedCallers) allows(commonName string, op string) bool {
+	for _, allowed := range a[commonName] {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
+
+// callerIdentity is the verified mTLS identity of an RPC's peer.
+type callerIdentity struct {
+	// commonName is the Subject Common Name of the peer's leaf certificate,
+	// checked against AllowedCallers.
+	commonName string
+	// fingerprint is the hex-encoded SHA-256 digest of the peer's leaf
+	// certificate, recorded on rows written by this caller so audits can
+	// attribute them to a specific CA instance. The callerFingerprint
+	// columns it's stamped into on precertificates and certificateStatus are
+	// added by sa/db/boulder_sa/20260215000000_add_caller_fingerprint.sql.
+	fingerprint string
+}
+
+// peerIdentity extracts the verified client certificate from ctx's gRPC
+// peer info. It returns an error if the RPC was not made over mTLS, or
+// presented no verified chain, which should never happen for an internal
+// control-plane RPC but is treated as fatal rather than silently allowed.
+func peerIdentity(ctx context.Context) (callerIdentity, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return callerIdentity{}, fmt.Errorf("no peer info in context")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return callerIdentity{}, fmt.Errorf("peer connection is not TLS")
+	}
+	chains := tlsInfo.State.VerifiedChains
+	if len(chains) == 0 || len(chains[0]) == 0 {
+		return callerIdentity{}, fmt.Errorf("peer presented no verified client certificate")
+	}
+	leaf := chains[0][0]
+	return callerIdentity{
+		commonName:  leaf.Subject.CommonName,
+		fingerprint: fingerprintCert(leaf),
+	}, nil
+}
+
+// fingerprintCert returns the hex-encoded SHA-256 digest of cert's raw DER,
+// used to attribute written rows to the CA instance that wrote them.
+func fingerprintCert(cert *x509.Certificate) string {
+	return sha256Hex(cert.Raw)
+}
+
+// authorizeCaller extracts the peer's verified client identity from ctx and
+// confirms it is allowed to perform op, returning the caller's certificate
+// fingerprint for callers that need to stamp it onto the rows they write.
+func (ssa *SQLStorageAuthority) authorizeCaller(ctx context.Context, op string) (string, error) {
+	if ssa.allowedCallers == nil {
+		// No AllowedCallers configured: this deployment hasn't opted into
+		// mTLS-identity authorization, so every caller is allowed (as
+		// before) and no fingerprint is recorded.
+		return "", nil
+	}
+	id, err := peerIdentity(ctx)
+	if err != nil {
+		return "", fmt.Errorf("authorizing caller for %s: %w", op, err)
+	}
+	if !ssa.allowedCallers.allows(id.commonName, op) {
+		return "", fmt.Errorf("caller %q is not authorized to call %s", id.commonName, op)
+	}
+	return id.fingerprint, nil
+}
+
+// sha256Hex returns the hex-encoded SHA-256 digest of data.
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,100 @@
+package sa
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestAllowedCallersAllows(t *testing.T) {
+	allowed := AllowedCallers{
+		"ca.boulder.internal": {"AddSerial", "AddPrecertificate"},
+	}
+
+	test.Assert(t, allowed.allows("ca.boulder.internal", "AddSerial"), "expected AddSerial to be allowed")
+	test.Assert(t, allowed.allows("ca.boulder.internal", "AddPrecertificate"), "expected AddPrecertificate to be allowed")
+	test.Assert(t, !allowed.allows("ca.boulder.internal", "RevokeCertificate"), "expected RevokeCertificate to be denied")
+	test.Assert(t, !allowed.allows("unknown-caller", "AddSerial"), "expected unconfigured caller to be denied")
+}
+
+func TestAllowedCallersNil(t *testing.T) {
+	var allowed AllowedCallers
+	test.Assert(t, !allowed.allows("anyone", "AddSerial"), "expected nil AllowedCallers to deny everything")
+}
+
+// tlsContext builds a context carrying the given peer.AuthInfo, the way gRPC
+// does internally, so peerIdentity can be exercised without a real network
+// connection.
+func tlsContext(authInfo credentials.AuthInfo) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: authInfo})
+}
+
+func TestPeerIdentityNoPeer(t *testing.T) {
+	_, err := peerIdentity(context.Background())
+	test.AssertError(t, err, "expected an error with no peer info in context")
+}
+
+func TestPeerIdentityNonTLS(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: nil})
+	_, err := peerIdentity(ctx)
+	test.AssertError(t, err, "expected an error for a non-TLS peer")
+}
+
+func TestPeerIdentityNoVerifiedChains(t *testing.T) {
+	ctx := tlsContext(credentials.TLSInfo{State: tls.ConnectionState{}})
+	_, err := peerIdentity(ctx)
+	test.AssertError(t, err, "expected an error with no verified chains")
+}
+
+func TestPeerIdentityReturnsLeaf(t *testing.T) {
+	leaf := &x509.Certificate{Subject: pkix.Name{CommonName: "ca.boulder.internal"}, Raw: []byte("fake-der-bytes")}
+	ctx := tlsContext(credentials.TLSInfo{State: tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{leaf}},
+	}})
+
+	id, err := peerIdentity(ctx)
+	test.AssertNotError(t, err, "peerIdentity should succeed with a verified chain")
+	test.AssertEquals(t, id.commonName, "ca.boulder.internal")
+	test.AssertEquals(t, id.fingerprint, fingerprintCert(leaf))
+}
+
+func TestAuthorizeCallerNilAllowedCallers(t *testing.T) {
+	ssa := &SQLStorageAuthority{}
+	fingerprint, err := ssa.authorizeCaller(context.Background(), "AddSerial")
+	test.AssertNotError(t, err, "authorizeCaller should allow everything when AllowedCallers is unconfigured")
+	test.AssertEquals(t, fingerprint, "")
+}
+
+func TestAuthorizeCallerAllowed(t *testing.T) {
+	leaf := &x509.Certificate{Subject: pkix.Name{CommonName: "ca.boulder.internal"}, Raw: []byte("fake-der-bytes")}
+	ctx := tlsContext(credentials.TLSInfo{State: tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{leaf}},
+	}})
+	ssa := &SQLStorageAuthority{
+		allowedCallers: AllowedCallers{"ca.boulder.internal": {"AddSerial"}},
+	}
+
+	fingerprint, err := ssa.authorizeCaller(ctx, "AddSerial")
+	test.AssertNotError(t, err, "expected AddSerial to be authorized")
+	test.AssertEquals(t, fingerprint, fingerprintCert(leaf))
+}
+
+func TestAuthorizeCallerDenied(t *testing.T) {
+	leaf := &x509.Certificate{Subject: pkix.Name{CommonName: "ca.boulder.internal"}, Raw: []byte("fake-der-bytes")}
+	ctx := tlsContext(credentials.TLSInfo{State: tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{leaf}},
+	}})
+	ssa := &SQLStorageAuthority{
+		allowedCallers: AllowedCallers{"ca.boulder.internal": {"AddSerial"}},
+	}
+
+	_, err := ssa.authorizeCaller(ctx, "RevokeCertificate")
+	test.AssertError(t, err, "expected RevokeCertificate to be denied for a caller only allowed AddSerial")
+}
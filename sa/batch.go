@@ -0,0 +1,340 @@
+package sa
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/letsencrypt/boulder/config"
+	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/db"
+	berrors "github.com/letsencrypt/boulder/errors"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+// BulkWriteConfig controls the behavior of batched write RPCs such as
+// AddPrecertificateBatch. Timeout bounds the whole batch transaction,
+// ThrottleTime is slept after a batch of more than one entry completes so
+// that a caller submitting many batches in a row doesn't saturate the DB
+// (single-entry calls are not delayed), and DocumentLimit caps how
+// many certificates a single AddPrecertificateBatch call will accept.
+type BulkWriteConfig struct {
+	Timeout       config.Duration `validate:"required"`
+	ThrottleTime  config.Duration `validate:"omitempty"`
+	DocumentLimit int             `validate:"required,min=1"`
+}
+
+// preparedBatchEntry holds a single AddCertificateBatchRequest entry
+// alongside the parsed certificate and derived serial we need in order to
+// insert it, plus its index in the original request so per-entry results
+// can be written back to the right slot.
+type preparedBatchEntry struct {
+	req       *sapb.AddCertificateRequest
+	parsed    *x509.Certificate
+	serial    string
+	origIndex int
+}
+
+// AddPrecertificate writes a record of a precertificate generation to the
+// DB. It is now a thin wrapper around AddPrecertificateBatch so that single
+// and bulk issuance share one code path. A repeat call for a precertificate
+// that is already recorded writes nothing and returns a DuplicateError.
+func (ssa *SQLStorageAuthority) AddPrecertificate(ctx context.Context, req *sapb.AddCertificateRequest) (*emptypb.Empty, error) {
+	if core.IsAnyNilOrZero(req.Der, req.Issued, req.RegID, req.IssuerID) {
+		return nil, errIncompleteRequest
+	}
+	resp, err := ssa.AddPrecertificateBatch(ctx, &sapb.AddCertificateBatchRequest{
+		Entries: []*sapb.AddCertificateRequest{req},
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch resp.Results[0].Status {
+	case sapb.AddCertificateBatchResponse_Error:
+		return nil, fmt.Errorf("inserting precertificate: %s", resp.Results[0].Error)
+	case sapb.AddCertificateBatchResponse_AlreadyExists:
+		return nil, berrors.DuplicateError("cannot add a duplicate cert")
+	default:
+		return &emptypb.Empty{}, nil
+	}
+}
+
+// AddPrecertificateBatch writes up to ssa.bulkWrite.DocumentLimit
+// precertificates in a single transaction, using multi-row inserts and
+// INSERT IGNORE (against the unique index on precertificates.serial) so
+// that a batch containing certs we've already recorded doesn't fail
+// outright; each entry gets its own Inserted/AlreadyExists/Error status.
+func (ssa *SQLStorageAuthority) AddPrecertificateBatch(ctx context.Context, req *sapb.AddCertificateBatchRequest) (*sapb.AddCertificateBatchResponse, error) {
+	if len(req.Entries) == 0 {
+		return nil, errIncompleteRequest
+	}
+	if len(req.Entries) > ssa.bulkWrite.DocumentLimit {
+		return nil, fmt.Errorf("batch of %d entries exceeds DocumentLimit of %d", len(req.Entries), ssa.bulkWrite.DocumentLimit)
+	}
+	// Authorized as "AddPrecertificate", not "AddPrecertificateBatch": batch
+	// insertion is an implementation detail of the same logical write
+	// operation that AddPrecertificate's single-cert wrapper performs, and
+	// operators configure AllowedCallers against the RPC name from the
+	// feature request, not this internal method name.
+	callerFingerprint, err := ssa.authorizeCaller(ctx, "AddPrecertificate")
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ssa.bulkWrite.Timeout.Duration)
+	defer cancel()
+
+	// Entries that fail validation or fail to parse get an Error result
+	// immediately and are excluded from the batch transaction below: one bad
+	// entry must not fail the whole batch.
+	results := make([]*sapb.AddCertificateBatchResponse_Result, len(req.Entries))
+	var parseable []preparedBatchEntry
+	for i, entry := range req.Entries {
+		if core.IsAnyNilOrZero(entry.Der, entry.Issued, entry.RegID, entry.IssuerID) {
+			results[i] = &sapb.AddCertificateBatchResponse_Result{
+				Status: sapb.AddCertificateBatchResponse_Error,
+				Error:  errIncompleteRequest.Error(),
+			}
+			continue
+		}
+		parsed, err := x509.ParseCertificate(entry.Der)
+		if err != nil {
+			results[i] = &sapb.AddCertificateBatchResponse_Result{
+				Status: sapb.AddCertificateBatchResponse_Error,
+				Error:  err.Error(),
+			}
+			continue
+		}
+		serial := core.SerialToString(parsed.SerialNumber)
+		results[i] = &sapb.AddCertificateBatchResponse_Result{Serial: serial}
+		parseable = append(parseable, preparedBatchEntry{req: entry, parsed: parsed, serial: serial, origIndex: i})
+	}
+
+	_, overallErr := db.WithTransaction(ctx, ssa.dbMap, func(txWithCtx db.Executor) (interface{}, error) {
+		if len(parseable) == 0 {
+			return nil, nil
+		}
+		existing, err := selectExistingSerials(txWithCtx, parseable)
+		if err != nil {
+			return nil, err
+		}
+
+		var toInsert []preparedBatchEntry
+		for _, entry := range parseable {
+			if existing[entry.serial] {
+				results[entry.origIndex].Status = sapb.AddCertificateBatchResponse_AlreadyExists
+				continue
+			}
+			toInsert = append(toInsert, entry)
+		}
+		if len(toInsert) == 0 {
+			return nil, nil
+		}
+
+		if err := bulkInsertPrecertificates(txWithCtx, toInsert, callerFingerprint); err != nil {
+			return nil, err
+		}
+		if err := bulkInsertCertificateStatuses(txWithCtx, ssa.clk.Now(), toInsert, callerFingerprint); err != nil {
+			return nil, err
+		}
+
+		// isRenewal still costs one checkFQDNSetExists lookup per entry (it
+		// depends on that entry's own DNSNames), but the issuedNames and
+		// keyHashes writes derived from it are collapsed into one multi-row
+		// INSERT each below, rather than one round trip per entry.
+		isRenewal := make(map[string]bool, len(toInsert))
+		for _, entry := range toInsert {
+			renewal, err := ssa.checkFQDNSetExists(txWithCtx.SelectOne, entry.parsed.DNSNames)
+			if err != nil {
+				return nil, err
+			}
+			isRenewal[entry.serial] = renewal
+		}
+		if err := bulkAddIssuedNames(txWithCtx, toInsert, isRenewal); err != nil {
+			return nil, err
+		}
+		if err := bulkAddKeyHashes(txWithCtx, toInsert); err != nil {
+			return nil, err
+		}
+		for _, entry := range toInsert {
+			err := recordCertificateEvent(txWithCtx, eventKindIssued, entry.serial, entry.req.IssuerID, entry.parsed.NotAfter, 0, ssa.clk.Now())
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, entry := range toInsert {
+			results[entry.origIndex].Status = sapb.AddCertificateBatchResponse_Inserted
+		}
+		return nil, nil
+	})
+	if overallErr != nil {
+		return nil, overallErr
+	}
+
+	for _, entry := range parseable {
+		if results[entry.origIndex].Status == sapb.AddCertificateBatchResponse_Inserted {
+			// A negative lookup (or a stale DER from a previous, since-deleted
+			// row) must not survive a fresh insert.
+			cacheInvalidate(ctx, ssa.certCache, "AddPrecertificateBatch", entry.serial)
+		}
+	}
+
+	// Only sleep for genuine multi-cert batches: AddPrecertificate's
+	// single-entry wrapper call must stay on the hot per-issuance path this
+	// RPC exists to speed up, not inherit a pacing delay meant for batches.
+	if ssa.bulkWrite.ThrottleTime.Duration > 0 && len(req.Entries) > 1 {
+		time.Sleep(ssa.bulkWrite.ThrottleTime.Duration)
+	}
+
+	return &sapb.AddCertificateBatchResponse{Results: results}, nil
+}
+
+// selectExistingSerials returns the subset of the given entries' serials
+// that are already present in the precertificates table, so the caller can
+// skip re-inserting them and report AlreadyExists instead. It locks the rows
+// (and, for serials with no row yet, the surrounding index gap) with FOR
+// UPDATE, the same pattern nextCRLNumber uses, so that two batches racing to
+// insert the same new serial serialize on this SELECT instead of both
+// passing the check and relying on INSERT IGNORE to silently pick a winner:
+// the second transaction blocks here until the first commits, and then
+// correctly observes the serial as already existing.
+func selectExistingSerials(txWithCtx db.Executor, entries []preparedBatchEntry) (map[string]bool, error) {
+	placeholders := make([]string, len(entries))
+	args := make([]interface{}, len(entries))
+	for i, e := range entries {
+		placeholders[i] = "?"
+		args[i] = e.serial
+	}
+	var rows []struct {
+		Serial string
+	}
+	query := fmt.Sprintf("SELECT serial FROM precertificates WHERE serial IN (%s) FOR UPDATE", strings.Join(placeholders, ","))
+	_, err := txWithCtx.Select(&rows, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		existing[row.Serial] = true
+	}
+	return existing, nil
+}
+
+// bulkInsertPrecertificates inserts all of the given entries' DER and
+// metadata into the precertificates table in one multi-row INSERT IGNORE,
+// making the whole operation safe to retry. callerFingerprint, if non-empty,
+// is stamped onto each row so audits can attribute it to the CA instance
+// that wrote it.
+func bulkInsertPrecertificates(txWithCtx db.Executor, entries []preparedBatchEntry, callerFingerprint string) error {
+	valuesSQL := make([]string, len(entries))
+	args := make([]interface{}, 0, len(entries)*6)
+	for i, e := range entries {
+		valuesSQL[i] = "(?, ?, ?, ?, ?, ?)"
+		args = append(args, e.serial, e.req.RegID, e.req.Der, time.Unix(0, e.req.Issued), e.parsed.NotAfter, callerFingerprint)
+	}
+	query := fmt.Sprintf(
+		"INSERT IGNORE INTO precertificates (serial, registrationID, der, issued, expires, callerFingerprint) VALUES %s",
+		strings.Join(valuesSQL, ","))
+	_, err := txWithCtx.Exec(query, args...)
+	return err
+}
+
+// bulkAddIssuedNames inserts one issuedNames row per (entry, DNS name) pair
+// across all of the given entries in a single multi-row INSERT, replacing
+// what used to be one addIssuedNames call (and DB round trip) per entry.
+func bulkAddIssuedNames(txWithCtx db.Executor, entries []preparedBatchEntry, isRenewal map[string]bool) error {
+	var valuesSQL []string
+	var args []interface{}
+	for _, e := range entries {
+		renewal := isRenewal[e.serial]
+		for _, name := range e.parsed.DNSNames {
+			valuesSQL = append(valuesSQL, "(?, ?, ?, ?)")
+			args = append(args, ReverseFQDN(name), e.serial, e.parsed.NotBefore, renewal)
+		}
+	}
+	if len(valuesSQL) == 0 {
+		return nil
+	}
+	query := fmt.Sprintf(
+		"INSERT INTO issuedNames (reversedName, serial, notBefore, renewal) VALUES %s",
+		strings.Join(valuesSQL, ","))
+	_, err := txWithCtx.Exec(query, args...)
+	return err
+}
+
+// bulkAddKeyHashes inserts one keyHashes row per entry across all of the
+// given entries in a single multi-row INSERT, replacing what used to be one
+// addKeyHash call (and DB round trip) per entry.
+func bulkAddKeyHashes(txWithCtx db.Executor, entries []preparedBatchEntry) error {
+	valuesSQL := make([]string, len(entries))
+	args := make([]interface{}, 0, len(entries)*3)
+	for i, e := range entries {
+		hash, err := core.KeyDigest(e.parsed.PublicKey)
+		if err != nil {
+			return err
+		}
+		valuesSQL[i] = "(?, ?, ?)"
+		args = append(args, hash[:], e.serial, e.parsed.NotAfter)
+	}
+	query := fmt.Sprintf(
+		"INSERT INTO keyHashes (keyHash, certSerial, certNotAfter) VALUES %s",
+		strings.Join(valuesSQL, ","))
+	_, err := txWithCtx.Exec(query, args...)
+	return err
+}
+
+// bulkInsertCertificateStatuses inserts the initial certificateStatus row
+// for each of the given entries in one multi-row INSERT IGNORE.
+// callerFingerprint, if non-empty, is stamped onto each row so audits can
+// attribute it to the CA instance that wrote it.
+func bulkInsertCertificateStatuses(txWithCtx db.Executor, now time.Time, entries []preparedBatchEntry, callerFingerprint string) error {
+	// certStatusFields() returns column names in whatever order the rest of
+	// the package maintains them in; look values up by name rather than
+	// relying on a hand-maintained positional slice staying in sync with it
+	// (see certStatusArgsForEntry). Copy before appending: certStatusFields() owns its
+	// returned slice, and appending directly onto it would risk mutating
+	// shared backing storage if it ever has spare capacity.
+	fieldNames := append(append([]string{}, certStatusFields()...), "callerFingerprint")
+	valuesSQL := make([]string, len(entries))
+	placeholders := "(" + strings.TrimSuffix(strings.Repeat("?,", len(fieldNames)), ",") + ")"
+	args := make([]interface{}, 0, len(entries)*len(fieldNames))
+	for i, e := range entries {
+		valuesSQL[i] = placeholders
+		args = append(args, certStatusArgsForEntry(e, now, callerFingerprint, fieldNames)...)
+	}
+	query := fmt.Sprintf(
+		"INSERT IGNORE INTO certificateStatus (%s) VALUES %s",
+		strings.Join(fieldNames, ","),
+		strings.Join(valuesSQL, ","))
+	_, err := txWithCtx.Exec(query, args...)
+	return err
+}
+
+// certStatusArgsForEntry returns the positional Exec args for a single
+// entry's certificateStatus row, in the order given by fieldNames, by
+// looking each value up by column name rather than relying on fieldNames'
+// order matching a hand-written positional list.
+func certStatusArgsForEntry(e preparedBatchEntry, now time.Time, callerFingerprint string, fieldNames []string) []interface{} {
+	fieldValues := map[string]interface{}{
+		"serial":                e.serial,
+		"status":                string(core.OCSPStatusGood),
+		"ocspLastUpdated":       now,
+		"revokedDate":           time.Time{},
+		"revokedReason":         0,
+		"lastExpirationNagSent": time.Time{},
+		"ocspResponse":          e.req.Ocsp,
+		"notAfter":              e.parsed.NotAfter,
+		"isExpired":             false,
+		"issuerID":              e.req.IssuerID,
+		"callerFingerprint":     callerFingerprint,
+	}
+	args := make([]interface{}, 0, len(fieldNames))
+	for _, name := range fieldNames {
+		args = append(args, fieldValues[name])
+	}
+	return args
+}
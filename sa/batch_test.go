@@ -0,0 +1,32 @@
+package sa
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestCertStatusArgsForEntryMatchesFieldOrder(t *testing.T) {
+	now := time.Now()
+	notAfter := now.Add(90 * 24 * time.Hour)
+	entry := preparedBatchEntry{
+		serial: "deadbeef",
+		req:    &sapb.AddCertificateRequest{IssuerID: 7, Ocsp: []byte("ocsp")},
+		parsed: &x509.Certificate{NotAfter: notAfter},
+	}
+
+	// Deliberately reversed from the order certStatusFields() would return,
+	// so this test fails if certStatusArgsForEntry ever goes back to
+	// assuming a fixed positional order instead of looking values up by name.
+	fieldNames := []string{"issuerID", "serial", "callerFingerprint"}
+
+	args := certStatusArgsForEntry(entry, now, "fingerprint123", fieldNames)
+
+	test.AssertEquals(t, len(args), len(fieldNames))
+	test.AssertEquals(t, args[0], int64(7))
+	test.AssertEquals(t, args[1], "deadbeef")
+	test.AssertEquals(t, args[2], "fingerprint123")
+}
@@ -0,0 +1,262 @@
+package sa
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	goredis "github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/letsencrypt/boulder/config"
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	rediscfg "github.com/letsencrypt/boulder/redis"
+)
+
+// CertCache is a read-through cache in front of certificate/precertificate
+// lookups by serial. Implementations must be safe for concurrent use.
+type CertCache interface {
+	// Get returns the cached certificate for serial. ok=false with a nil
+	// error means a genuine cache miss (including an expired entry); a
+	// non-nil error means the cache backend itself failed (e.g. a Redis
+	// outage), which callers must distinguish from a miss so it shows up as
+	// an error rather than a silent hit-rate drop.
+	Get(ctx context.Context, serial string) (cert *corepb.Certificate, ok bool, err error)
+	// Put stores cert under serial, to be evicted after ttl. A ttl of zero
+	// or less means the implementation's configured default TTL.
+	Put(ctx context.Context, serial string, cert *corepb.Certificate, ttl time.Duration) error
+	// Invalidate removes any cached entry for serial, so that a subsequent
+	// Get is guaranteed to miss.
+	Invalidate(ctx context.Context, serial string) error
+}
+
+// CacheConfig selects and configures the CertCache implementation wired
+// into GetPrecertificate and GetCertificate. Exactly one of InMemory or
+// Redis must be set; this is validated by ConfigValidator via the
+// `validate:"required_without"`/`excluded_with` tags below, so that setting
+// both (which NewCertCache would otherwise resolve by silently preferring
+// Redis and discarding InMemory) fails config validation instead.
+type CacheConfig struct {
+	InMemory *InMemoryCacheConfig `validate:"required_without=Redis,excluded_with=Redis"`
+	Redis    *RedisCacheConfig    `validate:"required_without=InMemory,excluded_with=InMemory"`
+}
+
+// InMemoryCacheConfig configures the in-process LRU CertCache.
+type InMemoryCacheConfig struct {
+	MaxEntries int             `validate:"required,min=1"`
+	TTL        config.Duration `validate:"required"`
+}
+
+// RedisCacheConfig configures the Redis-backed CertCache. It reuses
+// Boulder's existing Redis client configuration rather than inventing a new
+// one.
+type RedisCacheConfig struct {
+	rediscfg.Config
+	TTL config.Duration `validate:"required"`
+}
+
+var (
+	certCacheHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sa_cert_cache_hits_total",
+		Help: "Count of CertCache lookups that were served from cache, labeled by RPC.",
+	}, []string{"rpc"})
+	certCacheMisses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sa_cert_cache_misses_total",
+		Help: "Count of CertCache lookups that were not found in cache, labeled by RPC.",
+	}, []string{"rpc"})
+	certCacheErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sa_cert_cache_errors_total",
+		Help: "Count of CertCache operations that returned an error, labeled by RPC.",
+	}, []string{"rpc"})
+)
+
+// NewCertCache constructs the CertCache selected by cfg, registering its
+// Prometheus counters with stats. Unlike MustRegister, registering the same
+// counters against stats more than once (e.g. because a process constructs
+// more than one SQLStorageAuthority sharing a registry, as some test
+// helpers do) is not fatal: AlreadyRegisteredError is swallowed so only the
+// first registration against a given registry takes effect.
+func NewCertCache(cfg CacheConfig, stats prometheus.Registerer, rc *goredis.Ring) (CertCache, error) {
+	registerCertCacheMetrics(stats)
+	if cfg.Redis != nil {
+		return &redisCertCache{client: rc, ttl: cfg.Redis.TTL.Duration}, nil
+	}
+	return newLRUCertCache(cfg.InMemory.MaxEntries, cfg.InMemory.TTL.Duration), nil
+}
+
+// registerCertCacheMetrics registers the package-level CertCache counters
+// with stats, ignoring AlreadyRegisteredError so that constructing more
+// than one CertCache against the same registry doesn't panic the process.
+func registerCertCacheMetrics(stats prometheus.Registerer) {
+	for _, c := range []prometheus.Collector{certCacheHits, certCacheMisses, certCacheErrors} {
+		err := stats.Register(c)
+		if err != nil {
+			if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+				panic(err)
+			}
+		}
+	}
+}
+
+// lruEntry is a single cached certificate plus its expiry.
+type lruEntry struct {
+	cert    *corepb.Certificate
+	expires time.Time
+}
+
+// lruCertCache is a size- and TTL-bounded in-process CertCache. Eviction is
+// simple least-recently-inserted: once maxEntries is exceeded the oldest
+// entry by insertion order is dropped. This is intentionally simpler than a
+// true LRU (no access-order bookkeeping) because cert lookups are already
+// dominated by a small set of hot serials, and simplicity keeps the lock
+// held for a shorter time.
+type lruCertCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	defaultTTL time.Duration
+	entries    map[string]lruEntry
+	order      []string
+}
+
+func newLRUCertCache(maxEntries int, defaultTTL time.Duration) *lruCertCache {
+	return &lruCertCache{
+		maxEntries: maxEntries,
+		defaultTTL: defaultTTL,
+		entries:    make(map[string]lruEntry, maxEntries),
+	}
+}
+
+func (c *lruCertCache) Get(_ context.Context, serial string) (*corepb.Certificate, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[serial]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false, nil
+	}
+	return entry.cert, true, nil
+}
+
+func (c *lruCertCache) Put(_ context.Context, serial string, cert *corepb.Certificate, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = c.defaultTTL
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.entries[serial]; !exists {
+		if len(c.order) >= c.maxEntries {
+			oldest := c.order[0]
+			c.order = c.order[1:]
+			delete(c.entries, oldest)
+		}
+		c.order = append(c.order, serial)
+	}
+	c.entries[serial] = lruEntry{cert: cert, expires: time.Now().Add(ttl)}
+	return nil
+}
+
+func (c *lruCertCache) Invalidate(_ context.Context, serial string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.entries[serial]; !exists {
+		return nil
+	}
+	delete(c.entries, serial)
+	// Drop serial's insertion-order slot too, so a later Put of the same
+	// serial starts fresh and eviction never drops it via a stale slot.
+	for i, s := range c.order {
+		if s == serial {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// redisCertCache is a CertCache backed by Boulder's shared Redis client.
+// Entries are stored as serialized corepb.Certificate bytes under a
+// "cert-cache:" prefixed key so the keyspace doesn't collide with other
+// Redis-backed Boulder features sharing the same ring.
+type redisCertCache struct {
+	client *goredis.Ring
+	ttl    time.Duration
+}
+
+func redisCacheKey(serial string) string {
+	return "cert-cache:" + serial
+}
+
+func (c *redisCertCache) Get(ctx context.Context, serial string) (*corepb.Certificate, bool, error) {
+	raw, err := c.client.Get(ctx, redisCacheKey(serial)).Bytes()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	var cert corepb.Certificate
+	if err := proto.Unmarshal(raw, &cert); err != nil {
+		return nil, false, err
+	}
+	return &cert, true, nil
+}
+
+func (c *redisCertCache) Put(ctx context.Context, serial string, cert *corepb.Certificate, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = c.ttl
+	}
+	raw, err := proto.Marshal(cert)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, redisCacheKey(serial), raw, ttl).Err()
+}
+
+func (c *redisCertCache) Invalidate(ctx context.Context, serial string) error {
+	return c.client.Del(ctx, redisCacheKey(serial)).Err()
+}
+
+// cacheGet wraps a CertCache.Get with the hit/miss/error Prometheus
+// counters, labeled by the calling RPC's name. A cache backend error (e.g.
+// a Redis outage) is counted as an error, not a miss, and is treated as a
+// miss by the caller (falling through to the DB) rather than failing the
+// RPC outright.
+func cacheGet(ctx context.Context, cache CertCache, rpc string, serial string) (*corepb.Certificate, bool) {
+	if cache == nil {
+		return nil, false
+	}
+	cert, ok, err := cache.Get(ctx, serial)
+	switch {
+	case err != nil:
+		certCacheErrors.WithLabelValues(rpc).Inc()
+	case ok:
+		certCacheHits.WithLabelValues(rpc).Inc()
+	default:
+		certCacheMisses.WithLabelValues(rpc).Inc()
+	}
+	return cert, ok && err == nil
+}
+
+// cachePut wraps a CertCache.Put, counting failures so a misbehaving cache
+// backend shows up in monitoring instead of silently degrading hit rate.
+func cachePut(ctx context.Context, cache CertCache, rpc string, serial string, cert *corepb.Certificate, ttl time.Duration) {
+	if cache == nil {
+		return
+	}
+	if err := cache.Put(ctx, serial, cert, ttl); err != nil {
+		certCacheErrors.WithLabelValues(rpc).Inc()
+	}
+}
+
+// cacheInvalidate wraps a CertCache.Invalidate, counting failures. Called on
+// every write path that can make a cached entry stale or wrong: new
+// precertificate issuance and revocation.
+func cacheInvalidate(ctx context.Context, cache CertCache, rpc string, serial string) {
+	if cache == nil {
+		return
+	}
+	if err := cache.Invalidate(ctx, serial); err != nil {
+		certCacheErrors.WithLabelValues(rpc).Inc()
+	}
+}
@@ -0,0 +1,68 @@
+package sa
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestLRUCertCacheGetMiss(t *testing.T) {
+	c := newLRUCertCache(10, time.Minute)
+	cert, ok, err := c.Get(context.Background(), "nonexistent")
+	test.AssertNotError(t, err, "Get on empty cache")
+	test.Assert(t, !ok, "expected miss on empty cache")
+	test.Assert(t, cert == nil, "expected nil cert on miss")
+}
+
+func TestLRUCertCachePutGet(t *testing.T) {
+	c := newLRUCertCache(10, time.Minute)
+	want := &corepb.Certificate{Serial: "abc123"}
+
+	err := c.Put(context.Background(), "abc123", want, 0)
+	test.AssertNotError(t, err, "Put")
+
+	got, ok, err := c.Get(context.Background(), "abc123")
+	test.AssertNotError(t, err, "Get")
+	test.Assert(t, ok, "expected hit after Put")
+	test.AssertEquals(t, got.Serial, want.Serial)
+}
+
+func TestLRUCertCacheExpiry(t *testing.T) {
+	c := newLRUCertCache(10, 0)
+	err := c.Put(context.Background(), "abc123", &corepb.Certificate{Serial: "abc123"}, -time.Second)
+	test.AssertNotError(t, err, "Put")
+
+	_, ok, err := c.Get(context.Background(), "abc123")
+	test.AssertNotError(t, err, "Get")
+	test.Assert(t, !ok, "expected expired entry to miss")
+}
+
+func TestLRUCertCacheInvalidate(t *testing.T) {
+	c := newLRUCertCache(10, time.Minute)
+	err := c.Put(context.Background(), "abc123", &corepb.Certificate{Serial: "abc123"}, 0)
+	test.AssertNotError(t, err, "Put")
+
+	err = c.Invalidate(context.Background(), "abc123")
+	test.AssertNotError(t, err, "Invalidate")
+
+	_, ok, err := c.Get(context.Background(), "abc123")
+	test.AssertNotError(t, err, "Get")
+	test.Assert(t, !ok, "expected invalidated entry to miss")
+}
+
+func TestLRUCertCacheEviction(t *testing.T) {
+	c := newLRUCertCache(2, time.Minute)
+	ctx := context.Background()
+	test.AssertNotError(t, c.Put(ctx, "first", &corepb.Certificate{Serial: "first"}, 0), "Put first")
+	test.AssertNotError(t, c.Put(ctx, "second", &corepb.Certificate{Serial: "second"}, 0), "Put second")
+	test.AssertNotError(t, c.Put(ctx, "third", &corepb.Certificate{Serial: "third"}, 0), "Put third")
+
+	_, ok, _ := c.Get(ctx, "first")
+	test.Assert(t, !ok, "expected oldest entry to be evicted once maxEntries was exceeded")
+
+	_, ok, _ = c.Get(ctx, "third")
+	test.Assert(t, ok, "expected most recently inserted entry to survive")
+}
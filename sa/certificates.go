@@ -0,0 +1,45 @@
+package sa
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/letsencrypt/boulder/core"
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	"github.com/letsencrypt/boulder/db"
+	berrors "github.com/letsencrypt/boulder/errors"
+	bgrpc "github.com/letsencrypt/boulder/grpc"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+// GetCertificate takes a serial number and returns the corresponding final
+// (non-precert) certificate, or error if it does not exist. Reads go
+// through ssa.certCache first, if one is configured, mirroring
+// GetPrecertificate.
+func (ssa *SQLStorageAuthority) GetCertificate(ctx context.Context, req *sapb.Serial) (*corepb.Certificate, error) {
+	if req == nil || req.Serial == "" {
+		return nil, errIncompleteRequest
+	}
+	if !core.ValidSerial(req.Serial) {
+		return nil, fmt.Errorf("Invalid certificate serial %q", req.Serial)
+	}
+
+	const rpc = "GetCertificate"
+	if cert, ok := cacheGet(ctx, ssa.certCache, rpc, req.Serial); ok {
+		return cert, nil
+	}
+
+	cert, err := SelectCertificate(ssa.dbMap.WithContext(ctx), req.Serial)
+	if err != nil {
+		if db.IsNoRows(err) {
+			return nil, berrors.NotFoundError(
+				"certificate with serial %q not found",
+				req.Serial)
+		}
+		return nil, err
+	}
+
+	pbCert := bgrpc.CertToPB(cert)
+	cachePut(ctx, ssa.certCache, rpc, req.Serial, pbCert, 0)
+	return pbCert, nil
+}
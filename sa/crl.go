@@ -0,0 +1,151 @@
+package sa
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"time"
+
+	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/db"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+// crlEntryModel represents a single revoked-and-unexpired certificateStatus
+// row as read back when assembling a CRL (or CRL shard).
+type crlEntryModel struct {
+	Serial        string
+	RevokedDate   time.Time
+	RevokedReason int64
+}
+
+// crlShardModel tracks the last CRLNumber issued for a given
+// (issuerID, shardIdx) pair, stored in the `crlShards` table, so that the
+// CRLNumber extension stays monotonically increasing across restarts.
+//
+// The `crlShards` table, and the index on
+// certificateStatus(issuerID, status, notAfter) that makes the lookup in
+// GenerateCRL efficient, are added by
+// sa/db/boulder_sa/20260115000000_add_crl_shards.sql.
+type crlShardModel struct {
+	IssuerID      int64
+	ShardIdx      int64
+	LastCRLNumber int64
+	UpdatedAt     time.Time
+}
+
+// shardForSerial deterministically maps a certificate serial to one of
+// shardCount CRL shards, so that a given certificate's revocation always
+// lands on the same shard and shards can be generated independently and in
+// parallel. A shardCount of zero or less means "no sharding", i.e. shard 0
+// holds everything.
+func shardForSerial(serial string, shardCount int64) int64 {
+	if shardCount <= 0 {
+		return 0
+	}
+	return int64(crc32.ChecksumIEEE([]byte(serial))) % shardCount
+}
+
+// GenerateCRL returns the revoked, not-yet-expired certificates belonging to
+// a single issuer (optionally restricted to a single shard) so that the CA
+// can assemble a standards-compliant X.509 v2 CRL. GenerateCRL does not sign
+// anything; it is purely a data source, and also hands back the next
+// monotonic CRLNumber for this (issuer, shard) pair.
+func (ssa *SQLStorageAuthority) GenerateCRL(ctx context.Context, req *sapb.GenerateCRLRequest) (*sapb.CRL, error) {
+	if core.IsAnyNilOrZero(req.IssuerID, req.ThisUpdate) {
+		return nil, errIncompleteRequest
+	}
+	if req.NextUpdate == 0 && req.ExpiresIn == 0 {
+		return nil, errIncompleteRequest
+	}
+
+	thisUpdate := time.Unix(0, req.ThisUpdate)
+	nextUpdate := time.Unix(0, req.NextUpdate)
+	if req.ExpiresIn > 0 {
+		nextUpdate = thisUpdate.Add(time.Duration(req.ExpiresIn))
+	}
+
+	query := `SELECT serial, revokedDate, revokedReason FROM certificateStatus
+		WHERE issuerID = :issuerID AND status = :status AND notAfter > :notAfter`
+	args := map[string]interface{}{
+		"issuerID": req.IssuerID,
+		"status":   string(core.OCSPStatusRevoked),
+		"notAfter": thisUpdate,
+	}
+	if req.ShardCount > 0 {
+		if req.ShardIdx < 0 || req.ShardIdx >= req.ShardCount {
+			return nil, fmt.Errorf("shardIdx %d is out of range [0, %d)", req.ShardIdx, req.ShardCount)
+		}
+		query += " AND MOD(CRC32(serial), :shardCount) = :shardIdx"
+		args["shardCount"] = req.ShardCount
+		args["shardIdx"] = req.ShardIdx
+	}
+
+	var rows []crlEntryModel
+	_, err := ssa.dbMap.WithContext(ctx).Select(&rows, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*sapb.CRLEntry, 0, len(rows))
+	for _, row := range rows {
+		// Defense in depth: shardForSerial is the same CRC32-mod-shardCount
+		// logic as the MOD(CRC32(serial), ...) filter above, computed in Go
+		// rather than relying on MySQL's CRC32(). If the two ever disagree,
+		// drop the row rather than risk the same cert appearing in two
+		// shards' CRLs at once.
+		if req.ShardCount > 0 && shardForSerial(row.Serial, req.ShardCount) != req.ShardIdx {
+			continue
+		}
+		entries = append(entries, &sapb.CRLEntry{
+			Serial:        row.Serial,
+			RevokedDate:   row.RevokedDate.UnixNano(),
+			RevokedReason: int32(row.RevokedReason),
+		})
+	}
+
+	crlNumber, err := ssa.nextCRLNumber(ctx, req.IssuerID, req.ShardIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sapb.CRL{
+		ThisUpdate: req.ThisUpdate,
+		NextUpdate: nextUpdate.UnixNano(),
+		CrlNumber:  crlNumber,
+		Entries:    entries,
+	}, nil
+}
+
+// nextCRLNumber atomically advances and returns the last-issued CRL number
+// for the given (issuerID, shardIdx), creating the crlShards row on first
+// use so that callers never observe a missing shard as an error.
+func (ssa *SQLStorageAuthority) nextCRLNumber(ctx context.Context, issuerID int64, shardIdx int64) (int64, error) {
+	var crlNumber int64
+	_, err := db.WithTransaction(ctx, ssa.dbMap, func(txWithCtx db.Executor) (interface{}, error) {
+		var row crlShardModel
+		err := txWithCtx.SelectOne(&row,
+			`SELECT issuerID, shardIdx, lastCRLNumber, updatedAt FROM crlShards
+			WHERE issuerID = ? AND shardIdx = ? FOR UPDATE`,
+			issuerID, shardIdx)
+		if err != nil && !db.IsNoRows(err) {
+			return nil, err
+		}
+		if db.IsNoRows(err) {
+			crlNumber = 1
+			_, err = txWithCtx.Exec(
+				"INSERT INTO crlShards (issuerID, shardIdx, lastCRLNumber, updatedAt) VALUES (?, ?, ?, ?)",
+				issuerID, shardIdx, crlNumber, ssa.clk.Now())
+			return nil, err
+		}
+		crlNumber = row.LastCRLNumber + 1
+		_, err = txWithCtx.Exec(
+			"UPDATE crlShards SET lastCRLNumber = ?, updatedAt = ? WHERE issuerID = ? AND shardIdx = ?",
+			crlNumber, ssa.clk.Now(), issuerID, shardIdx)
+		return nil, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return crlNumber, nil
+}
@@ -0,0 +1,49 @@
+package sa
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestShardForSerialNoSharding(t *testing.T) {
+	test.AssertEquals(t, shardForSerial("deadbeef", 0), int64(0))
+	test.AssertEquals(t, shardForSerial("deadbeef", -1), int64(0))
+}
+
+func TestShardForSerialDeterministic(t *testing.T) {
+	serial := "03deadbeef0000000000000000000001"
+	first := shardForSerial(serial, 16)
+	for i := 0; i < 10; i++ {
+		test.AssertEquals(t, shardForSerial(serial, 16), first)
+	}
+}
+
+func TestShardForSerialInRange(t *testing.T) {
+	shardCount := int64(8)
+	serials := []string{
+		"03deadbeef0000000000000000000001",
+		"03deadbeef0000000000000000000002",
+		"0300000000000000000000000000000f",
+		"",
+	}
+	for _, serial := range serials {
+		shard := shardForSerial(serial, shardCount)
+		if shard < 0 || shard >= shardCount {
+			t.Errorf("shardForSerial(%q, %d) = %d, want in [0, %d)", serial, shardCount, shard, shardCount)
+		}
+	}
+}
+
+func TestShardForSerialSpreads(t *testing.T) {
+	shardCount := int64(4)
+	seen := make(map[int64]bool)
+	for i := 0; i < 100; i++ {
+		serial := fmt.Sprintf("03%030d", i)
+		seen[shardForSerial(serial, shardCount)] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("expected serials to spread across multiple shards, got only %d distinct shard(s)", len(seen))
+	}
+}
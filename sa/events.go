@@ -0,0 +1,148 @@
+package sa
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/letsencrypt/boulder/db"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+// certificateEventModel is a single row of the append-only certificateEvents
+// table. EventID is a BIGINT AUTO_INCREMENT primary key, so consumers can
+// use it as a durable, monotonically increasing cursor without needing to
+// compare timestamps.
+//
+// The certificateEvents table, and the eventCheckpoints table Checkpoint
+// writes to, are added by
+// sa/db/boulder_sa/20260201000000_add_certificate_events.sql.
+type certificateEventModel struct {
+	EventID       int64 `db:"eventID"`
+	Kind          string
+	Serial        string
+	IssuerID      int64
+	NotAfter      time.Time
+	RevokedReason int64
+	Timestamp     time.Time
+}
+
+// Event kinds stored in certificateEvents.Kind. These mirror
+// sapb.CertificateEvent_Kind.
+const (
+	eventKindIssued  = "issued"
+	eventKindRevoked = "revoked"
+	eventKindExpired = "expired"
+)
+
+// recordCertificateEvent inserts a single certificateEvents row as part of
+// an in-flight transaction. It's called from AddPrecertificateBatch and
+// from the revocation paths so that every event that downstream consumers
+// (the CT submitter, CRL updater, OCSP cache warmer) care about is recorded
+// atomically with the state change that produced it.
+func recordCertificateEvent(txWithCtx db.Executor, kind string, serial string, issuerID int64, notAfter time.Time, revokedReason int64, now time.Time) error {
+	return txWithCtx.Insert(&certificateEventModel{
+		Kind:          kind,
+		Serial:        serial,
+		IssuerID:      issuerID,
+		NotAfter:      notAfter,
+		RevokedReason: revokedReason,
+		Timestamp:     now,
+	})
+}
+
+// eventPollInterval is the starting poll interval used by
+// StreamCertificateEvents when it has caught up to the end of the
+// certificateEvents table; it backs off exponentially (capped at
+// eventPollIntervalMax) while idle and resets as soon as new rows appear.
+const (
+	eventPollInterval    = 100 * time.Millisecond
+	eventPollIntervalMax = 5 * time.Second
+	eventStreamBatchSize = 500
+)
+
+// nextPollInterval doubles current, capped at eventPollIntervalMax.
+func nextPollInterval(current time.Duration) time.Duration {
+	if current >= eventPollIntervalMax {
+		return eventPollIntervalMax
+	}
+	doubled := current * 2
+	if doubled > eventPollIntervalMax {
+		return eventPollIntervalMax
+	}
+	return doubled
+}
+
+// StreamCertificateEvents tails the certificateEvents table starting after
+// req.SinceEventID, sending batches of events to the client as they become
+// available. It polls with backoff rather than using a DB-native tailing
+// mechanism so it works unmodified against any of Boulder's supported DB
+// backends.
+func (ssa *SQLStorageAuthority) StreamCertificateEvents(req *sapb.StreamRequest, stream sapb.SA_StreamCertificateEventsServer) error {
+	if req == nil {
+		return errIncompleteRequest
+	}
+	ctx := stream.Context()
+	cursor := req.SinceEventID
+	interval := eventPollInterval
+
+	for {
+		var rows []certificateEventModel
+		_, err := ssa.dbMap.WithContext(ctx).Select(&rows,
+			"SELECT eventID, kind, serial, issuerID, notAfter, revokedReason, timestamp FROM certificateEvents WHERE eventID > ? ORDER BY eventID LIMIT ?",
+			cursor, eventStreamBatchSize)
+		if err != nil {
+			return err
+		}
+
+		if len(rows) == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(interval):
+			}
+			interval = nextPollInterval(interval)
+			continue
+		}
+		interval = eventPollInterval
+
+		for _, row := range rows {
+			err := stream.Send(&sapb.CertificateEvent{
+				EventID:       row.EventID,
+				Kind:          row.Kind,
+				Serial:        row.Serial,
+				IssuerID:      row.IssuerID,
+				NotAfter:      row.NotAfter.UnixNano(),
+				RevokedReason: int32(row.RevokedReason),
+				Timestamp:     row.Timestamp.UnixNano(),
+			})
+			if err != nil {
+				return err
+			}
+			cursor = row.EventID
+		}
+	}
+}
+
+// Checkpoint durably records a consumer's position in the
+// certificateEvents stream, so that a restarted consumer can resume
+// StreamCertificateEvents from where it left off instead of re-scanning
+// from the beginning.
+func (ssa *SQLStorageAuthority) Checkpoint(ctx context.Context, req *sapb.CheckpointRequest) (*emptypb.Empty, error) {
+	if req == nil || req.ConsumerID == "" {
+		return nil, errIncompleteRequest
+	}
+	_, err := db.WithTransaction(ctx, ssa.dbMap, func(txWithCtx db.Executor) (interface{}, error) {
+		_, err := txWithCtx.Exec(
+			`INSERT INTO eventCheckpoints (consumerID, eventID, updatedAt)
+			VALUES (?, ?, ?)
+			ON DUPLICATE KEY UPDATE eventID = VALUES(eventID), updatedAt = VALUES(updatedAt)`,
+			req.ConsumerID, req.EventID, ssa.clk.Now())
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
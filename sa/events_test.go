@@ -0,0 +1,29 @@
+package sa
+
+import (
+	"testing"
+	"time"
+
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestNextPollIntervalDoublesAndCaps(t *testing.T) {
+	interval := eventPollInterval
+	for i := 0; i < 20; i++ {
+		interval = nextPollInterval(interval)
+		if interval > eventPollIntervalMax {
+			t.Fatalf("nextPollInterval exceeded cap: got %s, max %s", interval, eventPollIntervalMax)
+		}
+	}
+	test.AssertEquals(t, interval, eventPollIntervalMax)
+}
+
+func TestNextPollIntervalAtCap(t *testing.T) {
+	test.AssertEquals(t, nextPollInterval(eventPollIntervalMax), eventPollIntervalMax)
+	test.AssertEquals(t, nextPollInterval(eventPollIntervalMax*2), eventPollIntervalMax)
+}
+
+func TestEventKindConstantsDistinct(t *testing.T) {
+	kinds := map[string]bool{eventKindIssued: true, eventKindRevoked: true, eventKindExpired: true}
+	test.AssertEquals(t, len(kinds), 3)
+}
@@ -0,0 +1,50 @@
+package sa
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/db"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+// RevokeCertificate marks a certificate as revoked in certificateStatus and
+// records a Revoked certificateEvents row in the same transaction, so that
+// StreamCertificateEvents consumers (the shard-based CRL generator among
+// them) see the revocation without having to re-scan certificateStatus. The
+// cached copy of the certificate, if any, is invalidated afterwards so a
+// stale pre-revocation DER or OCSP status can't keep being served from
+// ssa.certCache.
+func (ssa *SQLStorageAuthority) RevokeCertificate(ctx context.Context, req *sapb.RevokeCertificateRequest) (*emptypb.Empty, error) {
+	if core.IsAnyNilOrZero(req.Serial, req.Date, req.IssuerID) {
+		return nil, errIncompleteRequest
+	}
+	revokedDate := time.Unix(0, req.Date)
+
+	_, err := db.WithTransaction(ctx, ssa.dbMap, func(txWithCtx db.Executor) (interface{}, error) {
+		var row struct {
+			NotAfter time.Time
+		}
+		err := txWithCtx.SelectOne(&row, "SELECT notAfter FROM certificateStatus WHERE serial = ?", req.Serial)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = txWithCtx.Exec(
+			"UPDATE certificateStatus SET status = ?, revokedDate = ?, revokedReason = ?, ocspLastUpdated = ? WHERE serial = ?",
+			string(core.OCSPStatusRevoked), revokedDate, req.Reason, ssa.clk.Now(), req.Serial)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, recordCertificateEvent(txWithCtx, eventKindRevoked, req.Serial, req.IssuerID, row.NotAfter, req.Reason, ssa.clk.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+	cacheInvalidate(ctx, ssa.certCache, "RevokeCertificate", req.Serial)
+	return &emptypb.Empty{}, nil
+}